@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// Action is a pluggable recovery action invoked when a probe's failure
+// streak reaches its threshold. Implementations return the action's
+// output, if any, and an error if the action itself failed to run.
+type Action interface {
+	Execute(ctx context.Context, probe *ProbeConfig) (string, error)
+}
+
+// ResetActionConfig is one entry in a probe's reset action chain. Type
+// selects the implementation; only the block matching Type is read.
+type ResetActionConfig struct {
+	Type      string `yaml:"type"`
+	OnFailure string `yaml:"on_failure"`
+
+	Server  SSHServerConfig     `yaml:"server"`
+	Exec    ExecActionConfig    `yaml:"exec"`
+	Webhook WebhookActionConfig `yaml:"webhook"`
+	IPMI    IPMIActionConfig    `yaml:"ipmi"`
+}
+
+// on_failure values for ResetActionConfig. OnFailureStop, the default,
+// stops the chain on the first failing action; OnFailureNext moves on to
+// the next action instead.
+const (
+	OnFailureStop = "stop"
+	OnFailureNext = "next"
+)
+
+type contextKey string
+
+// failureStreakContextKey is the ctx key triggerReset uses to pass the
+// probe's current failure-streak counter down to Action implementations,
+// since the Action interface itself can't grow a parameter without
+// breaking every implementation.
+const failureStreakContextKey contextKey = "failure_streak"
+
+// WithFailureStreak attaches a probe's current failure-streak counter to
+// ctx for the reset action chain to read via FailureStreakFromContext.
+func WithFailureStreak(ctx context.Context, streak int) context.Context {
+	return context.WithValue(ctx, failureStreakContextKey, streak)
+}
+
+// FailureStreakFromContext returns the failure-streak counter attached by
+// WithFailureStreak, or 0 if none was attached.
+func FailureStreakFromContext(ctx context.Context) int {
+	streak, _ := ctx.Value(failureStreakContextKey).(int)
+	return streak
+}
+
+var actionBuilders = map[string]func(ResetActionConfig) (Action, error){}
+
+// registerAction makes a reset action type available for use in a probe's
+// `reset` config block. Implementations call this from an init func.
+func registerAction(name string, builder func(ResetActionConfig) (Action, error)) {
+	actionBuilders[name] = builder
+}
+
+func buildAction(conf ResetActionConfig) (Action, error) {
+	builder, ok := actionBuilders[conf.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown reset action type %q", conf.Type)
+	}
+	return builder(conf)
+}
+
+// buildActions compiles a probe's reset action chain in order.
+func buildActions(confs []ResetActionConfig) ([]Action, error) {
+	actions := make([]Action, 0, len(confs))
+	for _, c := range confs {
+		action, err := buildAction(c)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// runResetActions executes a probe's reset action chain in order. The
+// chain ends at the first action that succeeds. An action that fails also
+// stops the chain unless it is configured with `on_failure: next`, in
+// which case the next action runs instead. The output and error of the
+// last action run are returned.
+func runResetActions(ctx context.Context, conf *ProbeConfig) (string, error) {
+	var output string
+	var err error
+	for i, action := range conf.actions {
+		output, err = action.Execute(ctx, conf)
+		if err != nil && conf.Reset[i].OnFailure == OnFailureNext {
+			continue
+		}
+		break
+	}
+	return output, err
+}
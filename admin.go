@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// registerAdminRoutes wires the small control API onto mux: listing probe
+// state, and pausing, resuming, or immediately resetting a probe by name.
+func registerAdminRoutes(mux *http.ServeMux, sup *supervisor) {
+	mux.HandleFunc("/probes", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		states := sup.list()
+		statuses := make([]probeStatus, 0, len(states))
+		for _, state := range states {
+			statuses = append(statuses, state.snapshot())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(statuses)
+	})
+
+	mux.HandleFunc("/probes/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		rest := strings.TrimPrefix(r.URL.Path, "/probes/")
+		parts := strings.SplitN(rest, "/", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			http.Error(w, "expected /probes/{name}/{action}", http.StatusNotFound)
+			return
+		}
+		name, action := parts[0], parts[1]
+
+		state, ok := sup.get(name)
+		if !ok {
+			http.Error(w, "unknown probe", http.StatusNotFound)
+			return
+		}
+
+		switch action {
+		case "pause":
+			state.setPaused(true)
+		case "resume":
+			state.setPaused(false)
+		case "reset-now":
+			select {
+			case state.resetNow <- struct{}{}:
+			default:
+				// a reset is already pending
+			}
+		default:
+			http.Error(w, "unknown action", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
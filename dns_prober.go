@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+)
+
+func init() {
+	registerProber("dns", &dnsProber{})
+}
+
+// ErrorDNSProbeMissingName is returned when a `dns://` target has no
+// lookup name in its path.
+var ErrorDNSProbeMissingName = errors.New("dns probe url is missing a lookup name")
+
+// dnsProber probes a `dns://server/name?type=A` target by resolving name
+// against server, failing on NXDOMAIN or timeout.
+type dnsProber struct{}
+
+func (p *dnsProber) Check(ctx context.Context, conf *ProbeConfig, target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return err
+	}
+
+	server := u.Host
+	if strings.LastIndex(server, ":") < 0 {
+		server += ":53"
+	}
+	name := strings.TrimPrefix(u.Path, "/")
+	if name == "" {
+		return ErrorDNSProbeMissingName
+	}
+
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server)
+		},
+	}
+
+	queryType := strings.ToUpper(u.Query().Get("type"))
+	if queryType == "CNAME" {
+		_, err = resolver.LookupCNAME(ctx, name)
+		return err
+	}
+	_, err = resolver.LookupHost(ctx, name)
+	return err
+}
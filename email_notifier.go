@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+func init() {
+	registerNotifier("email", func(conf NotifierConfig) (Notifier, error) {
+		return &emailNotifier{conf: conf.Email}, nil
+	})
+}
+
+// EmailNotifierConfig holds settings for the SMTP email notifier.
+type EmailNotifierConfig struct {
+	SMTPHost string   `yaml:"smtp_host"`
+	SMTPPort int      `yaml:"smtp_port"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// emailNotifier sends an event as a plain-text email over SMTP.
+type emailNotifier struct {
+	conf EmailNotifierConfig
+}
+
+func (n *emailNotifier) Notify(ctx context.Context, event NotifyEvent) error {
+	addr := fmt.Sprintf("%s:%d", n.conf.SMTPHost, n.conf.SMTPPort)
+	var auth smtp.Auth
+	if n.conf.Username != "" {
+		auth = smtp.PlainAuth("", n.conf.Username, n.conf.Password, n.conf.SMTPHost)
+	}
+
+	subject := fmt.Sprintf("[network-watchdog] %s: %s", event.Probe, event.Type)
+	body := fmt.Sprintf(
+		"probe: %s\nevent: %s\nreason: %s\nfailure_streak: %d\naction_output: %s\ntimestamp: %s\n",
+		event.Probe, event.Type, event.Reason, event.FailureStreak, event.ActionOutput, event.Timestamp)
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s",
+		n.conf.From, strings.Join(n.conf.To, ", "), subject, body)
+
+	return smtp.SendMail(addr, auth, n.conf.From, n.conf.To, []byte(msg))
+}
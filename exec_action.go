@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+)
+
+func init() {
+	registerAction("exec", func(conf ResetActionConfig) (Action, error) {
+		return &execAction{conf: conf.Exec}, nil
+	})
+}
+
+// ExecActionConfig holds the settings for the exec reset action.
+type ExecActionConfig struct {
+	Command string            `yaml:"command"`
+	Args    []string          `yaml:"args"`
+	Env     map[string]string `yaml:"env"`
+}
+
+// execAction runs a local command as the recovery action, for custom
+// scripts or appliances with no SSH surface.
+type execAction struct {
+	conf ExecActionConfig
+}
+
+func (a *execAction) Execute(ctx context.Context, probe *ProbeConfig) (string, error) {
+	cmd := exec.CommandContext(ctx, a.conf.Command, a.conf.Args...)
+	if len(a.conf.Env) > 0 {
+		cmd.Env = os.Environ()
+		for k, v := range a.conf.Env {
+			cmd.Env = append(cmd.Env, k+"="+v)
+		}
+	}
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	return out.String(), err
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// ErrorNoHostKeyVerification is returned when a probe's reset server has
+// none of known_hosts_file, host_key, or insecure_ignore_host_key configured.
+var ErrorNoHostKeyVerification = errors.New("no SSH host key verification configured: set known_hosts_file, host_key, or insecure_ignore_host_key")
+
+// buildHostKeyCallback builds the ssh.HostKeyCallback used to verify a
+// probe's reset server, preferring an explicitly configured known_hosts
+// file, then an inline host_key, and refusing to connect otherwise unless
+// insecure_ignore_host_key is set. known_hosts_file is never defaulted to
+// the real ~/.ssh/known_hosts: that would make verification depend on
+// whatever happens to be on the machine running the watchdog, silently
+// overriding a pinned host_key with unrelated entries.
+func buildHostKeyCallback(conf *SSHServerConfig) (ssh.HostKeyCallback, error) {
+	if conf.KnownHostsFile != "" {
+		// Explicitly configured: a missing file is almost always a typo,
+		// not an invitation to fall back to a weaker check, so fail loudly
+		// instead of silently moving on.
+		if _, err := os.Stat(conf.KnownHostsFile); err != nil {
+			return nil, fmt.Errorf("known_hosts_file %q: %w", conf.KnownHostsFile, err)
+		}
+		return knownhosts.New(conf.KnownHostsFile)
+	}
+
+	if conf.HostKey != "" {
+		pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(conf.HostKey))
+		if err != nil {
+			return nil, err
+		}
+		return ssh.FixedHostKey(pubKey), nil
+	}
+
+	if conf.InsecureIgnoreHostKey {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+
+	return nil, ErrorNoHostKeyVerification
+}
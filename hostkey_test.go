@@ -0,0 +1,112 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// newTestHostKey generates an in-memory ed25519 host key and returns both
+// the ssh.Signer a test server authenticates with and the
+// "ssh-ed25519 AAAA..." line a client would pin for it.
+func newTestHostKey(t *testing.T) (ssh.Signer, string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generating ed25519 key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatalf("building signer: %v", err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatalf("building public key: %v", err)
+	}
+	return signer, string(ssh.MarshalAuthorizedKey(sshPub))
+}
+
+// listenAndServeOneHandshake starts a one-shot SSH server on loopback,
+// authenticating with hostKey, and returns its address plus a channel that
+// receives the handshake's result. A real socket is used, rather than
+// net.Pipe, because exchangeVersions writes each side's banner before
+// reading the peer's: over a synchronous, unbuffered net.Pipe both ends
+// block in Write at once and the handshake deadlocks.
+func listenAndServeOneHandshake(t *testing.T, hostKey ssh.Signer) (string, <-chan error) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening on loopback: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer ln.Close()
+		conn, err := ln.Accept()
+		if err != nil {
+			done <- err
+			return
+		}
+		defer conn.Close()
+
+		config := &ssh.ServerConfig{NoClientAuth: true}
+		config.AddHostKey(hostKey)
+		_, _, _, err = ssh.NewServerConn(conn, config)
+		done <- err
+	}()
+	return ln.Addr().String(), done
+}
+
+func TestBuildHostKeyCallbackAcceptsPinnedHostKey(t *testing.T) {
+	hostKey, authorizedKey := newTestHostKey(t)
+	addr, serverDone := listenAndServeOneHandshake(t, hostKey)
+
+	callback, err := buildHostKeyCallback(&SSHServerConfig{HostKey: authorizedKey})
+	if err != nil {
+		t.Fatalf("buildHostKeyCallback: %v", err)
+	}
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("dialing test server: %v", err)
+	}
+	sshConn, _, _, err := ssh.NewClientConn(conn, addr, &ssh.ClientConfig{
+		HostKeyCallback: callback,
+	})
+	if err != nil {
+		t.Fatalf("expected the pinned host key to be accepted, got: %v", err)
+	}
+	sshConn.Close()
+
+	if err := <-serverDone; err != nil {
+		t.Fatalf("server handshake failed: %v", err)
+	}
+}
+
+func TestBuildHostKeyCallbackRejectsMismatchedHostKey(t *testing.T) {
+	hostKey, _ := newTestHostKey(t)
+	_, otherAuthorizedKey := newTestHostKey(t)
+	addr, serverDone := listenAndServeOneHandshake(t, hostKey)
+
+	callback, err := buildHostKeyCallback(&SSHServerConfig{HostKey: otherAuthorizedKey})
+	if err != nil {
+		t.Fatalf("buildHostKeyCallback: %v", err)
+	}
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("dialing test server: %v", err)
+	}
+	_, _, _, err = ssh.NewClientConn(conn, addr, &ssh.ClientConfig{
+		HostKeyCallback: callback,
+	})
+	if err == nil {
+		t.Fatal("expected a mismatched host key to be rejected, got nil error")
+	}
+	conn.Close()
+
+	<-serverDone
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+)
+
+func init() {
+	registerProber("http", &httpProber{})
+	registerProber("https", &httpProber{})
+}
+
+// httpProber is the legacy plain-URL probe: GET the URL and require a 200
+// or 204 response.
+type httpProber struct{}
+
+func (p *httpProber) Check(ctx context.Context, conf *ProbeConfig, target string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	if conf.httpClient == nil {
+		conf.httpClient = &http.Client{Timeout: conf.timeout}
+	}
+	resp, err := conf.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	if resp.Body != nil {
+		// for reusing connection
+		ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	probeLog(conf).WithField("status_code", resp.StatusCode).Debug("http probe response received")
+	if resp.StatusCode == http.StatusOK {
+		// Allow 200 response code
+		return nil
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		return ErrorStatusCodeIsNot204
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import "context"
+
+func init() {
+	registerAction("ipmi", func(conf ResetActionConfig) (Action, error) {
+		return &ipmiAction{conf: conf.IPMI}, nil
+	})
+}
+
+// IPMIActionConfig holds the settings for the ipmi reset action.
+type IPMIActionConfig struct {
+	Host     string `yaml:"host"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// ipmiAction power cycles a machine over IPMI via ipmitool, for bare-metal
+// hosts and appliances with no SSH surface. It is implemented on top of
+// the exec backend.
+type ipmiAction struct {
+	conf IPMIActionConfig
+}
+
+func (a *ipmiAction) Execute(ctx context.Context, probe *ProbeConfig) (string, error) {
+	backend := &execAction{conf: ExecActionConfig{
+		Command: "ipmitool",
+		Args: []string{
+			"-H", a.conf.Host,
+			"-U", a.conf.Username,
+			// -E reads the password from IPMI_PASSWORD instead of taking
+			// it on the command line, where it would be visible to any
+			// local user via ps/proc.
+			"-E",
+			"chassis", "power", "cycle",
+		},
+		Env: map[string]string{"IPMI_PASSWORD": a.conf.Password},
+	}}
+	return backend.Execute(ctx, probe)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	lumberjack "gopkg.in/natefinch/lumberjack.v2"
+)
+
+// LogConfig configures the watchdog's structured logger.
+type LogConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+	File   string `yaml:"file"`
+
+	MaxSize    int  `yaml:"max_size"`
+	MaxBackups int  `yaml:"max_backups"`
+	MaxAge     int  `yaml:"max_age"`
+	Compress   bool `yaml:"compress"`
+}
+
+var logger = logrus.New()
+
+// configureLogger applies a LogConfig to the package logger. It is called
+// at startup and on every config reload, after the config file is parsed;
+// until the first call logger uses logrus's text-to-stderr defaults.
+func configureLogger(conf LogConfig) error {
+	if conf.Format == "json" {
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logger.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	}
+
+	level := logrus.InfoLevel
+	if conf.Level != "" {
+		parsed, err := logrus.ParseLevel(conf.Level)
+		if err != nil {
+			return err
+		}
+		level = parsed
+	} else if verbose {
+		level = logrus.DebugLevel
+	}
+	logger.SetLevel(level)
+
+	var out io.Writer = os.Stdout
+	if conf.File != "" {
+		out = &lumberjack.Logger{
+			Filename:   conf.File,
+			MaxSize:    conf.MaxSize,
+			MaxBackups: conf.MaxBackups,
+			MaxAge:     conf.MaxAge,
+			Compress:   conf.Compress,
+		}
+	}
+	logger.SetOutput(out)
+
+	return nil
+}
+
+// probeLog returns a logger pre-populated with a probe's stable fields.
+func probeLog(conf *ProbeConfig) *logrus.Entry {
+	return logger.WithFields(logrus.Fields{
+		"probe":      conf.Name,
+		"probe_type": probeType(conf),
+		"target":     conf.primaryTarget(),
+	})
+}
@@ -1,22 +1,19 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
-	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
-	ping "github.com/sparrc/go-ping"
-	"golang.org/x/crypto/ssh"
+	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
 )
 
@@ -33,22 +30,37 @@ type ProbeConfig struct {
 	Interval  string `yaml:"interval"`
 	DownTimes int    `yaml:"down_times"`
 
-	Server struct {
-		Hostname     string `yaml:"hostname"`
-		Username     string `yaml:"username"`
-		Password     string `yaml:"password"`
-		KeyFile      string `yaml:"key_file"`
-		ResetCommand string `yaml:"reset_command"`
-	} `yaml:"server"`
+	// ProbeURLs, when set, checks multiple targets on every tick instead
+	// of the single ProbeURL; Aggregation decides when that's a failure.
+	ProbeURLs   []string `yaml:"probe_urls"`
+	Aggregation string   `yaml:"aggregation"`
+
+	// Server configures the legacy single ssh reset action. New configs
+	// should use Reset instead; if Reset is empty and Server is set, it
+	// is used to synthesize a single ssh reset action.
+	Server SSHServerConfig     `yaml:"server"`
+	Reset  []ResetActionConfig `yaml:"reset"`
+
+	// Notifiers selects which top-level notifiers fire for this probe's
+	// reset events, by name; empty means all of them.
+	Notifiers []string `yaml:"notifiers"`
 
 	timeout    time.Duration
 	interval   time.Duration
 	httpClient *http.Client
+	actions    []Action
+	notifiers  []Notifier
 }
 
 // Config is the main config
 type Config struct {
-	Probes []ProbeConfig `yaml:"probes"`
+	Probes        []ProbeConfig    `yaml:"probes"`
+	MetricsListen string           `yaml:"metrics_listen"`
+	Log           LogConfig        `yaml:"log"`
+	Notifiers     []NotifierConfig `yaml:"notifiers"`
+	// NotifyDedupWindow is a time.ParseDuration string; within it, a probe
+	// won't renotify for the same event type. Defaults to 5m.
+	NotifyDedupWindow string `yaml:"notify_dedup_window"`
 }
 
 // errors
@@ -59,247 +71,281 @@ var (
 
 var confFilePath string
 var verbose bool
+var enableMetrics bool
 
 func init() {
 	flag.StringVar(&confFilePath, "config", "", "config file path")
 	flag.BoolVar(&verbose, "verbose", false, "verbose mode")
+	flag.BoolVar(&enableMetrics, "metrics", false, "enable the Prometheus metrics and pprof HTTP server")
 }
 
-// PublicKeyFile get ssh key from file
-func PublicKeyFile(file string) (ssh.AuthMethod, error) {
-	buffer, err := ioutil.ReadFile(file)
+// resetServer runs a probe's reset action chain, logs the outcome, and
+// records the resulting metrics.
+func resetServer(ctx context.Context, conf *ProbeConfig) (string, error) {
+	start := time.Now()
+	out, err := runResetActions(ctx, conf)
+	duration := time.Since(start)
+	resetDuration.WithLabelValues(conf.Name, conf.primaryTarget()).Observe(duration.Seconds())
+
+	plog := probeLog(conf).WithFields(logrus.Fields{
+		"duration_ms": duration.Milliseconds(),
+		"output":      out,
+	})
 	if err != nil {
-		return nil, err
-	}
-
-	key, err := ssh.ParsePrivateKey(buffer)
-	if err != nil {
-		return nil, err
-	}
-	return ssh.PublicKeys(key), nil
-}
-
-func resetServer(conf *ProbeConfig) (string, error) {
-	// Authentication
-	var method []ssh.AuthMethod
-	if conf.Server.Password != "" {
-		method = append(method, ssh.Password(conf.Server.Password))
-	}
-	if conf.Server.KeyFile != "" {
-		// alternatively, we could use a public key
-		authMethod, err := PublicKeyFile(conf.Server.KeyFile)
-		if err != nil {
-			return "", err
-		}
-		method = append(method, authMethod)
-	}
-	config := &ssh.ClientConfig{
-		User: conf.Server.Username,
-		Auth: method,
-		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
-			return nil
-		},
-	}
-	var addr string
-	if strings.LastIndex(conf.Server.Hostname, ":") >= 0 {
-		addr = conf.Server.Hostname
+		resetAttemptsTotal.WithLabelValues(conf.Name, conf.primaryTarget(), "failure").Inc()
+		plog.WithField("error", err).Error("reset action failed")
 	} else {
-		// using ssh default port 22
-		addr = conf.Server.Hostname + ":22"
-	}
-	// Connect
-	client, err := ssh.Dial("tcp", addr, config)
-	if err != nil {
-		return "", err
+		resetAttemptsTotal.WithLabelValues(conf.Name, conf.primaryTarget(), "success").Inc()
+		plog.Info("reset action succeeded")
 	}
-	// Create a session. It is one session per command.
-	session, err := client.NewSession()
-	if err != nil {
-		return "", err
-	}
-	defer session.Close()
-
-	var b bytes.Buffer  // import "bytes"
-	session.Stdout = &b // get output
-	// you can also pass what gets input to the stdin, allowing you to pipe
-	// content from client to server
-	//      session.Stdin = bytes.NewBufferString("My input")
-
-	// Finally, run the command
-	err = session.Run(conf.Server.ResetCommand)
-	return b.String(), err
+	return out, err
 }
 
-func pingProbe(conf *ProbeConfig) error {
-	parts := strings.SplitN(conf.ProbeURL, " ", 2)
-	if len(parts) < 2 {
-		panic("malformed ping probe url")
+// triggerReset runs a probe's reset action chain, notifying observers
+// before and after. It returns the failure-streak counter that should
+// follow (0 on success, the streak unchanged on failure) and the reset
+// action's own error, if any.
+func triggerReset(ctx context.Context, conf *ProbeConfig, plog *logrus.Entry, counter int) (int, error) {
+	plog.WithField("failure_streak", counter).Warn("resetting server")
+	notifyAll(ctx, conf, NotifyEvent{
+		Type:          NotifyThresholdReached,
+		Probe:         conf.Name,
+		Reason:        "probe failure streak reached threshold",
+		FailureStreak: counter,
+		Timestamp:     time.Now(),
+	})
+
+	resetStart := time.Now()
+	out, err := resetServer(WithFailureStreak(ctx, counter), conf)
+	resetEvent := NotifyEvent{
+		Probe:         conf.Name,
+		FailureStreak: counter,
+		ActionOutput:  out,
+		Duration:      time.Since(resetStart),
+		Timestamp:     time.Now(),
 	}
-
-	target := parts[1]
-	pinger, err := ping.NewPinger(target)
-	if err != nil {
-		return err
-	}
-
-	pinger.SetPrivileged(true)
-	pinger.Count = 3
-	pinger.Timeout = 5 * time.Second
-
-	if verbose {
-		pinger.OnRecv = func(pkt *ping.Packet) {
-			fmt.Printf("%d bytes from %s: icmp_seq=%d time=%v\n",
-				pkt.Nbytes, pkt.IPAddr, pkt.Seq, pkt.Rtt)
-		}
-		pinger.OnFinish = func(stats *ping.Statistics) {
-			fmt.Printf("\n--- %s ping statistics ---\n", stats.Addr)
-			fmt.Printf("%d packets transmitted, %d packets received, %v%% packet loss\n",
-				stats.PacketsSent, stats.PacketsRecv, stats.PacketLoss)
-			fmt.Printf("round-trip min/avg/max/stddev = %v/%v/%v/%v\n",
-				stats.MinRtt, stats.AvgRtt, stats.MaxRtt, stats.StdDevRtt)
-		}
-	}
-
-	pinger.Run()
-	if pinger.PacketsRecv < pinger.Count {
-		return ErrorPingProbeUnfinished
-	}
-
-	return nil
-}
-
-func requestProbe(conf *ProbeConfig) error {
-	req, err := http.NewRequest(http.MethodGet, conf.ProbeURL, nil)
-	if err != nil {
-		return err
-	}
-	if conf.httpClient == nil {
-		conf.httpClient = &http.Client{Timeout: conf.timeout}
-	}
-	resp, err := conf.httpClient.Do(req)
-	if err != nil {
-		return err
-	}
-	if resp.Body != nil {
-		// for reusing connection
-		ioutil.ReadAll(resp.Body)
-		resp.Body.Close()
-	}
-	if resp.StatusCode == http.StatusOK {
-		// Allow 200 response code
-		return nil
-	}
-	if resp.StatusCode != http.StatusNoContent {
-		return ErrorStatusCodeIsNot204
+	if err == nil {
+		resetEvent.Type = NotifyResetSucceeded
+		resetEvent.Reason = "reset action completed"
+		counter = 0
+	} else {
+		resetEvent.Type = NotifyResetFailed
+		resetEvent.Reason = err.Error()
 	}
-	return err
+	notifyAll(ctx, conf, resetEvent)
+	return counter, err
 }
 
-func loopCheck(stopCh <-chan struct{}, conf *ProbeConfig) {
+// loopCheck runs a single probe's check loop until ctx is cancelled. It
+// honors state.isPaused (skipping checks without resetting the failure
+// streak) and state.resetNow (triggering an out-of-band reset on admin
+// request), and keeps state.lastError current for the admin API.
+func loopCheck(ctx context.Context, conf *ProbeConfig, state *probeState) {
 	ticker := time.NewTicker(conf.interval)
 	defer ticker.Stop()
 
-	log.Println("starting server", conf.Name, "probe check")
+	plog := probeLog(conf)
+	plog.Info("starting probe check loop")
 
 	var err error
 	counter := 0
+	attempt := 0
 loop:
 	for {
 		select {
-		case <-stopCh:
+		case <-ctx.Done():
 			break loop
+		case <-state.resetNow:
+			counter, err = triggerReset(ctx, conf, plog, conf.DownTimes)
+			state.setLastError(err)
 		case <-ticker.C:
-			if strings.HasPrefix(conf.ProbeURL, "ping ") {
-				err = pingProbe(conf)
-			} else {
-				err = requestProbe(conf)
+			if state.isPaused() {
+				continue
 			}
+			attempt++
+			checkStart := time.Now()
+			err = checkProbes(ctx, conf)
+			durationMs := time.Since(checkStart).Milliseconds()
+
+			entry := plog.WithFields(logrus.Fields{
+				"attempt":     attempt,
+				"duration_ms": durationMs,
+			})
 			if err != nil {
 				counter++
-				log.Println("server", conf.Name, "probe check error:", err, "counter:", counter)
+				probeChecksTotal.WithLabelValues(conf.Name, conf.primaryTarget(), "failure").Inc()
+				entry.WithFields(logrus.Fields{
+					"failure_streak": counter,
+					"error":          err,
+				}).Warn("probe check failed")
 			} else {
 				// mark health
 				counter = 0
-				if verbose {
-					log.Println("server", conf.Name, "probe check success")
-				}
+				probeChecksTotal.WithLabelValues(conf.Name, conf.primaryTarget(), "success").Inc()
+				probeLastSuccessTimestamp.WithLabelValues(conf.Name, conf.primaryTarget()).Set(float64(time.Now().Unix()))
+				entry.Debug("probe check succeeded")
 			}
+			probeFailureStreak.WithLabelValues(conf.Name, conf.primaryTarget()).Set(float64(counter))
+			state.setLastError(err)
 			if counter >= conf.DownTimes {
-				log.Println("resetting server", conf.Name, "...")
-
-				var s string
-				s, err = resetServer(conf)
-				log.Println(s)
-
-				if err == nil {
-					// reset counter
-					counter = 0
-				} else {
-					log.Println("resetting server", conf.Name, "error:", err)
-				}
+				counter, err = triggerReset(ctx, conf, plog, counter)
+				state.setLastError(err)
 			}
 		}
 	}
 }
 
-func main() {
-	flag.Parse()
-
-	if confFilePath == "" {
-		flag.Usage()
-		os.Exit(1)
-		return
-	}
-
-	confFile, err := os.Open(confFilePath)
+// loadConfig reads and validates the config file at path, filling in
+// defaults and building the runtime-only fields (timeouts, actions,
+// notifiers) on every probe. It is used both at startup and on a
+// SIGHUP-triggered reload.
+func loadConfig(path string) (*Config, error) {
+	confFile, err := os.Open(path)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
+	defer confFile.Close()
 
 	data, err := ioutil.ReadAll(confFile)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	var conf Config
-	err = yaml.Unmarshal(data, &conf)
-	if err != nil {
-		log.Fatal(err)
+	if err = yaml.Unmarshal(data, &conf); err != nil {
+		return nil, err
 	}
 
 	if len(conf.Probes) <= 0 {
-		err = errors.New("no probes configured")
-		log.Fatal(err)
+		return nil, errors.New("no probes configured")
+	}
+
+	dedupWindow := 5 * time.Minute
+	if conf.NotifyDedupWindow != "" {
+		dedupWindow, err = time.ParseDuration(conf.NotifyDedupWindow)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if conf.Log.Level != "" {
+		// validated here so configureLogger cannot fail after a reload
+		// has been accepted
+		if _, err = logrus.ParseLevel(conf.Log.Level); err != nil {
+			return nil, err
+		}
+	}
+	allNotifiers, err := buildNotifiers(conf.Notifiers)
+	if err != nil {
+		return nil, err
 	}
 
 	for i := range conf.Probes {
 		if conf.Probes[i].Name == "" {
 			conf.Probes[i].Name = conf.Probes[i].Server.Hostname
 		}
-		if conf.Probes[i].ProbeURL == "" {
+		if conf.Probes[i].ProbeURL == "" && len(conf.Probes[i].ProbeURLs) == 0 {
 			conf.Probes[i].ProbeURL = DefaultGenerate204ProbeURL
 		}
 		conf.Probes[i].timeout, err = time.ParseDuration(conf.Probes[i].Timeout)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 		conf.Probes[i].interval, err = time.ParseDuration(conf.Probes[i].Interval)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 		if conf.Probes[i].DownTimes <= 0 {
-			err = errors.New("invalid down times")
-			log.Fatal(err)
+			return nil, errors.New("invalid down times")
+		}
+		if len(conf.Probes[i].Reset) == 0 && conf.Probes[i].Server.Hostname != "" {
+			// backwards compatibility: synthesize a single ssh reset
+			// action from the legacy top-level `server` block.
+			conf.Probes[i].Reset = []ResetActionConfig{{
+				Type:      "ssh",
+				OnFailure: OnFailureStop,
+				Server:    conf.Probes[i].Server,
+			}}
+		}
+		conf.Probes[i].actions, err = buildActions(conf.Probes[i].Reset)
+		if err != nil {
+			return nil, err
+		}
+		if len(conf.Probes[i].actions) == 0 {
+			return nil, fmt.Errorf("probe %q has no reset actions configured (set reset or the legacy server block)", conf.Probes[i].Name)
+		}
+		if len(conf.Probes[i].Notifiers) > 0 {
+			for _, name := range conf.Probes[i].Notifiers {
+				n, ok := allNotifiers[name]
+				if !ok {
+					return nil, fmt.Errorf("probe %q references unknown notifier %q", conf.Probes[i].Name, name)
+				}
+				conf.Probes[i].notifiers = append(conf.Probes[i].notifiers, n)
+			}
+		} else {
+			for _, n := range allNotifiers {
+				conf.Probes[i].notifiers = append(conf.Probes[i].notifiers, n)
+			}
+		}
+	}
+
+	// applied only once the whole file has validated, so a rejected
+	// reload leaves the running window in place
+	setNotifyDedupWindow(dedupWindow)
+	return &conf, nil
+}
+
+func main() {
+	flag.Parse()
+
+	if confFilePath == "" {
+		flag.Usage()
+		os.Exit(1)
+		return
+	}
+
+	conf, err := loadConfig(confFilePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = configureLogger(conf.Log); err != nil {
+		log.Fatal(err)
+	}
+
+	sup := newSupervisor()
+
+	if enableMetrics {
+		listen := conf.MetricsListen
+		if listen == "" {
+			listen = ":9127"
 		}
+		startMetricsServer(listen, sup)
 	}
 
-	stopCh := make(chan struct{})
 	for i := range conf.Probes {
-		go loopCheck(stopCh, &conf.Probes[i])
+		sup.start(&conf.Probes[i])
 	}
 
-	shutdown := make(chan os.Signal, 1)
-	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
-	<-shutdown
-	close(stopCh)
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	for s := range sig {
+		if s == syscall.SIGHUP {
+			newConf, err := loadConfig(confFilePath)
+			if err != nil {
+				logger.WithField("error", err).Error("config reload failed, keeping running config")
+				continue
+			}
+			if err = configureLogger(newConf.Log); err != nil {
+				logger.WithField("error", err).Error("config reload failed, keeping running config")
+				continue
+			}
+			conf = newConf
+			sup.reload(conf.Probes)
+			logger.Info("config reloaded")
+			continue
+		}
+		logger.WithField("signal", s).Info("shutting down")
+		sup.stopAll()
+		return
+	}
 }
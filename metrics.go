@@ -0,0 +1,91 @@
+package main
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prometheus metrics for probe checks and reset actions.
+var (
+	probeChecksTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "watchdog_probe_checks_total",
+			Help: "Total number of probe checks performed, labeled by result.",
+		},
+		[]string{"name", "probe_url", "result"},
+	)
+
+	probeCheckDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "watchdog_probe_check_duration_seconds",
+			Help: "Duration of a single probe check in seconds.",
+		},
+		[]string{"name", "probe_url"},
+	)
+
+	probeFailureStreak = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "watchdog_probe_failure_streak",
+			Help: "Current number of consecutive failed checks for a probe.",
+		},
+		[]string{"name", "probe_url"},
+	)
+
+	probeLastSuccessTimestamp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "watchdog_probe_last_success_timestamp_seconds",
+			Help: "Unix timestamp of the last successful probe check.",
+		},
+		[]string{"name", "probe_url"},
+	)
+
+	resetAttemptsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "watchdog_reset_attempts_total",
+			Help: "Total number of server reset attempts, labeled by result.",
+		},
+		[]string{"name", "probe_url", "result"},
+	)
+
+	resetDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "watchdog_reset_duration_seconds",
+			Help: "Duration of a server reset attempt in seconds.",
+		},
+		[]string{"name", "probe_url"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		probeChecksTotal,
+		probeCheckDuration,
+		probeFailureStreak,
+		probeLastSuccessTimestamp,
+		resetAttemptsTotal,
+		resetDuration,
+	)
+}
+
+// startMetricsServer starts the embedded Prometheus metrics, pprof, and
+// admin HTTP server in the background. It does not block the caller.
+func startMetricsServer(addr string, sup *supervisor) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	registerAdminRoutes(mux, sup)
+
+	go func() {
+		logger.WithField("addr", addr).Info("starting metrics server")
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			logger.WithField("error", err).Error("metrics server error")
+		}
+	}()
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// NotifyEventType identifies which lifecycle point triggered a
+// notification.
+type NotifyEventType string
+
+// Notification lifecycle points fired from loopCheck.
+const (
+	NotifyThresholdReached NotifyEventType = "threshold_reached"
+	NotifyResetSucceeded   NotifyEventType = "reset_succeeded"
+	NotifyResetFailed      NotifyEventType = "reset_failed"
+)
+
+// NotifyEvent describes a probe reset lifecycle event passed to every
+// notifier registered for the probe.
+type NotifyEvent struct {
+	Type          NotifyEventType
+	Probe         string
+	Reason        string
+	FailureStreak int
+	ActionOutput  string
+	Duration      time.Duration
+	Timestamp     time.Time
+}
+
+// Notifier delivers a NotifyEvent to an external system.
+type Notifier interface {
+	Notify(ctx context.Context, event NotifyEvent) error
+}
+
+// NotifierConfig is one entry in the top-level `notifiers` config block.
+type NotifierConfig struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+
+	Webhook  WebhookNotifierConfig  `yaml:"webhook"`
+	Email    EmailNotifierConfig    `yaml:"email"`
+	Slack    SlackNotifierConfig    `yaml:"slack"`
+	Telegram TelegramNotifierConfig `yaml:"telegram"`
+}
+
+var notifierBuilders = map[string]func(NotifierConfig) (Notifier, error){}
+
+// registerNotifier makes a notifier type available for use in the
+// `notifiers` config block. Implementations call this from an init func.
+func registerNotifier(name string, builder func(NotifierConfig) (Notifier, error)) {
+	notifierBuilders[name] = builder
+}
+
+func buildNotifier(conf NotifierConfig) (Notifier, error) {
+	builder, ok := notifierBuilders[conf.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown notifier type %q", conf.Type)
+	}
+	return builder(conf)
+}
+
+// buildNotifiers compiles the top-level notifiers block, keyed by name.
+func buildNotifiers(confs []NotifierConfig) (map[string]Notifier, error) {
+	notifiers := make(map[string]Notifier, len(confs))
+	for _, c := range confs {
+		notifier, err := buildNotifier(c)
+		if err != nil {
+			return nil, err
+		}
+		notifiers[c.Name] = notifier
+	}
+	return notifiers, nil
+}
+
+// notifyDedup suppresses repeat notifications for the same probe and
+// event type within a configurable window, so a flapping probe doesn't
+// spam operators.
+type notifyDedup struct {
+	window time.Duration
+
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+func newNotifyDedup(window time.Duration) *notifyDedup {
+	return &notifyDedup{window: window, last: make(map[string]time.Time)}
+}
+
+func (d *notifyDedup) allow(probe string, eventType NotifyEventType) bool {
+	if d.window <= 0 {
+		return true
+	}
+	key := probe + "|" + string(eventType)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if last, ok := d.last[key]; ok && time.Since(last) < d.window {
+		return false
+	}
+	d.last[key] = time.Now()
+	return true
+}
+
+// notifyTracker is the process-wide dedup window; configured by loadConfig
+// from Config.NotifyDedupWindow. It is replaced wholesale on a SIGHUP
+// config reload while probe goroutines are concurrently reading it via
+// notifyAll, so access goes through notifyTrackerMu rather than a bare
+// package var.
+var (
+	notifyTrackerMu sync.Mutex
+	notifyTracker   = newNotifyDedup(5 * time.Minute)
+)
+
+// setNotifyDedupWindow replaces the process-wide dedup window, for use
+// at startup and on config reload.
+func setNotifyDedupWindow(window time.Duration) {
+	notifyTrackerMu.Lock()
+	notifyTracker = newNotifyDedup(window)
+	notifyTrackerMu.Unlock()
+}
+
+func currentNotifyTracker() *notifyDedup {
+	notifyTrackerMu.Lock()
+	defer notifyTrackerMu.Unlock()
+	return notifyTracker
+}
+
+// notifyAll sends event to every notifier registered for conf, subject to
+// the dedup window.
+func notifyAll(ctx context.Context, conf *ProbeConfig, event NotifyEvent) {
+	if len(conf.notifiers) == 0 || !currentNotifyTracker().allow(conf.Name, event.Type) {
+		return
+	}
+	for _, n := range conf.notifiers {
+		if err := n.Notify(ctx, event); err != nil {
+			probeLog(conf).WithField("error", err).Error("notifier failed")
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	ping "github.com/sparrc/go-ping"
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	registerProber("ping", &pingProber{})
+}
+
+// pingProber is the legacy `ping <target>` probe.
+type pingProber struct{}
+
+func (p *pingProber) Check(ctx context.Context, conf *ProbeConfig, target string) error {
+	parts := strings.SplitN(target, " ", 2)
+	if len(parts) < 2 {
+		panic("malformed ping probe url")
+	}
+
+	pinger, err := ping.NewPinger(parts[1])
+	if err != nil {
+		return err
+	}
+
+	pinger.SetPrivileged(true)
+	pinger.Count = 3
+	pinger.Timeout = 5 * time.Second
+
+	plog := probeLog(conf)
+	pinger.OnRecv = func(pkt *ping.Packet) {
+		plog.WithFields(logrus.Fields{
+			"bytes": pkt.Nbytes,
+			"addr":  pkt.IPAddr.String(),
+			"seq":   pkt.Seq,
+			"rtt":   pkt.Rtt,
+		}).Debug("ping reply received")
+	}
+	pinger.OnFinish = func(stats *ping.Statistics) {
+		plog.WithFields(logrus.Fields{
+			"packets_sent": stats.PacketsSent,
+			"packets_recv": stats.PacketsRecv,
+			"packet_loss":  stats.PacketLoss,
+			"rtt_min":      stats.MinRtt,
+			"rtt_avg":      stats.AvgRtt,
+			"rtt_max":      stats.MaxRtt,
+		}).Debug("ping finished")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		pinger.Run()
+		close(done)
+	}()
+	select {
+	case <-ctx.Done():
+		pinger.Stop()
+		return ctx.Err()
+	case <-done:
+	}
+
+	if pinger.PacketsRecv < pinger.Count {
+		return ErrorPingProbeUnfinished
+	}
+	return nil
+}
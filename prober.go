@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Prober performs a single check against a probe target and reports
+// success or failure.
+type Prober interface {
+	Check(ctx context.Context, conf *ProbeConfig, target string) error
+}
+
+// Aggregation policies for a probe with multiple targets. AggregationAny,
+// the default, fails the probe if any target fails (legacy, single-target
+// behavior). AggregationAll only fails the probe once every target fails,
+// which absorbs a single upstream hiccup.
+const (
+	AggregationAny = "any"
+	AggregationAll = "all"
+)
+
+var probers = map[string]Prober{}
+
+// registerProber makes a Prober available for a URL scheme, or "ping" for
+// the legacy `ping <target>` probe syntax. Implementations call this from
+// an init func.
+func registerProber(scheme string, prober Prober) {
+	probers[scheme] = prober
+}
+
+func proberFor(target string) (Prober, error) {
+	scheme := probeScheme(target)
+	prober, ok := probers[scheme]
+	if !ok {
+		return nil, fmt.Errorf("no prober registered for scheme %q", scheme)
+	}
+	return prober, nil
+}
+
+func probeScheme(target string) string {
+	if strings.HasPrefix(target, "ping ") {
+		return "ping"
+	}
+	if idx := strings.Index(target, "://"); idx >= 0 {
+		return target[:idx]
+	}
+	return "http"
+}
+
+// probeType returns the short probe kind used for logging and metrics:
+// the scheme of the probe's first target.
+func probeType(conf *ProbeConfig) string {
+	targets := conf.targets()
+	if len(targets) == 0 {
+		return ""
+	}
+	return probeScheme(targets[0])
+}
+
+// targets returns the list of URLs a probe checks on every tick.
+func (conf *ProbeConfig) targets() []string {
+	if len(conf.ProbeURLs) > 0 {
+		return conf.ProbeURLs
+	}
+	return []string{conf.ProbeURL}
+}
+
+// primaryTarget returns the representative target used to label aggregate
+// logs and metrics that apply to the whole probe rather than one target:
+// the first entry of targets().
+func (conf *ProbeConfig) primaryTarget() string {
+	return conf.targets()[0]
+}
+
+// checkProbe runs a single target's Prober, honoring conf.timeout and the
+// parent ctx, and recording the per-target duration metric.
+func checkProbe(ctx context.Context, conf *ProbeConfig, target string) error {
+	start := time.Now()
+	defer func() {
+		probeCheckDuration.WithLabelValues(conf.Name, target).Observe(time.Since(start).Seconds())
+	}()
+
+	prober, err := proberFor(target)
+	if err != nil {
+		return err
+	}
+
+	if conf.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, conf.timeout)
+		defer cancel()
+	}
+
+	return prober.Check(ctx, conf, target)
+}
+
+// checkProbes runs every target configured for a probe and aggregates the
+// result according to conf.Aggregation.
+func checkProbes(ctx context.Context, conf *ProbeConfig) error {
+	targets := conf.targets()
+
+	var lastErr error
+	failures := 0
+	for _, target := range targets {
+		if err := checkProbe(ctx, conf, target); err != nil {
+			failures++
+			lastErr = err
+		}
+	}
+
+	if conf.Aggregation == AggregationAll {
+		if failures == len(targets) {
+			return lastErr
+		}
+		return nil
+	}
+	return lastErr
+}
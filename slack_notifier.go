@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+func init() {
+	registerNotifier("slack", func(conf NotifierConfig) (Notifier, error) {
+		return &slackNotifier{conf: conf.Slack}, nil
+	})
+}
+
+// SlackNotifierConfig holds settings for the Slack incoming webhook
+// notifier.
+type SlackNotifierConfig struct {
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// slackNotifier posts the event to a Slack incoming webhook.
+type slackNotifier struct {
+	conf SlackNotifierConfig
+}
+
+func (n *slackNotifier) Notify(ctx context.Context, event NotifyEvent) error {
+	text := fmt.Sprintf("*%s* probe `%s`: %s (failure_streak=%d)",
+		event.Type, event.Probe, event.Reason, event.FailureStreak)
+	payload, err := json.Marshal(slackMessage{Text: text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.conf.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("slack notifier received status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func init() {
+	registerAction("ssh", func(conf ResetActionConfig) (Action, error) {
+		return &sshAction{conf: conf.Server}, nil
+	})
+}
+
+// SSHServerConfig holds the settings for the ssh reset action.
+type SSHServerConfig struct {
+	Hostname     string `yaml:"hostname"`
+	Username     string `yaml:"username"`
+	Password     string `yaml:"password"`
+	KeyFile      string `yaml:"key_file"`
+	ResetCommand string `yaml:"reset_command"`
+
+	KnownHostsFile        string   `yaml:"known_hosts_file"`
+	HostKey               string   `yaml:"host_key"`
+	HostKeyAlgorithms     []string `yaml:"host_key_algorithms"`
+	InsecureIgnoreHostKey bool     `yaml:"insecure_ignore_host_key"`
+}
+
+// sshAction runs a reset command on a remote server over SSH. It is the
+// original recovery mechanism, and the one synthesized from a probe's
+// legacy `server` block.
+type sshAction struct {
+	conf SSHServerConfig
+}
+
+func (a *sshAction) Execute(ctx context.Context, probe *ProbeConfig) (string, error) {
+	// Authentication
+	var method []ssh.AuthMethod
+	if a.conf.Password != "" {
+		method = append(method, ssh.Password(a.conf.Password))
+	}
+	if a.conf.KeyFile != "" {
+		// alternatively, we could use a public key
+		authMethod, err := PublicKeyFile(a.conf.KeyFile)
+		if err != nil {
+			return "", err
+		}
+		method = append(method, authMethod)
+	}
+	hostKeyCallback, err := buildHostKeyCallback(&a.conf)
+	if err != nil {
+		return "", err
+	}
+	config := &ssh.ClientConfig{
+		User:              a.conf.Username,
+		Auth:              method,
+		HostKeyCallback:   hostKeyCallback,
+		HostKeyAlgorithms: a.conf.HostKeyAlgorithms,
+	}
+	var addr string
+	if strings.LastIndex(a.conf.Hostname, ":") >= 0 {
+		addr = a.conf.Hostname
+	} else {
+		// using ssh default port 22
+		addr = a.conf.Hostname + ":22"
+	}
+	// Connect, honoring ctx cancellation on the dial itself; ssh has no
+	// context-aware Dial, so the net.Dial is done separately.
+	var d net.Dialer
+	netConn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(netConn, addr, config)
+	if err != nil {
+		return "", err
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	defer client.Close()
+	// Create a session. It is one session per command.
+	session, err := client.NewSession()
+	if err != nil {
+		return "", err
+	}
+	defer session.Close()
+
+	var b bytes.Buffer
+	session.Stdout = &b // get output
+	// you can also pass what gets input to the stdin, allowing you to pipe
+	// content from client to server
+	//      session.Stdin = bytes.NewBufferString("My input")
+
+	// Run the command in the background so ctx cancellation (a shutdown or
+	// reload while the reset is in flight) can interrupt it; session.Run
+	// itself has no context support, so closing the client is what unblocks it.
+	result := make(chan error, 1)
+	go func() {
+		result <- session.Run(a.conf.ResetCommand)
+	}()
+
+	select {
+	case <-ctx.Done():
+		client.Close()
+		<-result
+		return b.String(), ctx.Err()
+	case err := <-result:
+		return b.String(), err
+	}
+}
+
+// PublicKeyFile get ssh key from file
+func PublicKeyFile(file string) (ssh.AuthMethod, error) {
+	buffer, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ssh.ParsePrivateKey(buffer)
+	if err != nil {
+		return nil, err
+	}
+	return ssh.PublicKeys(key), nil
+}
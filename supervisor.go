@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// probeState tracks one running probe check loop: how to stop it,
+// whether an operator has paused it, its last observed error, and a
+// channel used to request an immediate reset. All fields are safe for
+// concurrent use from the loop goroutine and the admin API.
+type probeState struct {
+	conf     *ProbeConfig
+	cancel   context.CancelFunc
+	resetNow chan struct{}
+
+	mu        sync.Mutex
+	paused    bool
+	lastError string
+}
+
+func newProbeState(conf *ProbeConfig, cancel context.CancelFunc) *probeState {
+	return &probeState{
+		conf:     conf,
+		cancel:   cancel,
+		resetNow: make(chan struct{}, 1),
+	}
+}
+
+func (s *probeState) setPaused(paused bool) {
+	s.mu.Lock()
+	s.paused = paused
+	s.mu.Unlock()
+}
+
+func (s *probeState) isPaused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.paused
+}
+
+func (s *probeState) setLastError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.lastError = err.Error()
+	} else {
+		s.lastError = ""
+	}
+}
+
+// probeStatus is the JSON representation of a probe's state for the
+// admin API.
+type probeStatus struct {
+	Name      string `json:"name"`
+	ProbeURL  string `json:"probe_url"`
+	Paused    bool   `json:"paused"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+func (s *probeState) snapshot() probeStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return probeStatus{
+		Name:      s.conf.Name,
+		ProbeURL:  s.conf.primaryTarget(),
+		Paused:    s.paused,
+		LastError: s.lastError,
+	}
+}
+
+// supervisor runs and supervises the set of probe check loops, letting
+// them be started, stopped, paused, resumed, or reloaded without
+// restarting the process.
+type supervisor struct {
+	wg sync.WaitGroup
+
+	mu     sync.Mutex
+	probes map[string]*probeState
+}
+
+func newSupervisor() *supervisor {
+	return &supervisor{probes: make(map[string]*probeState)}
+}
+
+// start launches a probe's check loop goroutine and tracks it.
+func (s *supervisor) start(conf *ProbeConfig) {
+	ctx, cancel := context.WithCancel(context.Background())
+	state := newProbeState(conf, cancel)
+
+	s.mu.Lock()
+	s.probes[conf.Name] = state
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		loopCheck(ctx, conf, state)
+	}()
+}
+
+// stop cancels a running probe's check loop, if any, and stops tracking
+// it. It does not wait for the loop goroutine to exit.
+func (s *supervisor) stop(name string) {
+	s.mu.Lock()
+	state, ok := s.probes[name]
+	if ok {
+		delete(s.probes, name)
+	}
+	s.mu.Unlock()
+	if ok {
+		state.cancel()
+	}
+}
+
+// stopAll cancels every running probe and waits for their loops to exit.
+func (s *supervisor) stopAll() {
+	s.mu.Lock()
+	names := make([]string, 0, len(s.probes))
+	for name := range s.probes {
+		names = append(names, name)
+	}
+	s.mu.Unlock()
+
+	for _, name := range names {
+		s.stop(name)
+	}
+	s.wg.Wait()
+}
+
+func (s *supervisor) get(name string) (*probeState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.probes[name]
+	return state, ok
+}
+
+func (s *supervisor) list() []*probeState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	states := make([]*probeState, 0, len(s.probes))
+	for _, state := range s.probes {
+		states = append(states, state)
+	}
+	return states
+}
+
+// reload replaces the running probe set with probes: every probe in the
+// new config is (re)started with its fresh settings, and any probe no
+// longer present is stopped. Probes are always restarted rather than
+// diffed field-by-field, trading a brief counter reset for much simpler
+// reload logic.
+func (s *supervisor) reload(probes []ProbeConfig) {
+	seen := make(map[string]bool, len(probes))
+	for i := range probes {
+		conf := &probes[i]
+		seen[conf.Name] = true
+		s.stop(conf.Name)
+		s.start(conf)
+	}
+
+	s.mu.Lock()
+	var stale []string
+	for name := range s.probes {
+		if !seen[name] {
+			stale = append(stale, name)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, name := range stale {
+		s.stop(name)
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"net"
+	"strings"
+)
+
+func init() {
+	registerProber("tcp", &tcpProber{})
+}
+
+// tcpProber probes a `tcp://host:port` target by dialing it; success
+// means the connection was established.
+type tcpProber struct{}
+
+func (p *tcpProber) Check(ctx context.Context, conf *ProbeConfig, target string) error {
+	addr := strings.TrimPrefix(target, "tcp://")
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+func init() {
+	registerNotifier("telegram", func(conf NotifierConfig) (Notifier, error) {
+		return &telegramNotifier{conf: conf.Telegram}, nil
+	})
+}
+
+// TelegramNotifierConfig holds settings for the Telegram bot API
+// notifier.
+type TelegramNotifierConfig struct {
+	BotToken string `yaml:"bot_token"`
+	ChatID   string `yaml:"chat_id"`
+}
+
+// telegramNotifier sends the event as a chat message via the Telegram bot
+// API.
+type telegramNotifier struct {
+	conf TelegramNotifierConfig
+}
+
+func (n *telegramNotifier) Notify(ctx context.Context, event NotifyEvent) error {
+	text := fmt.Sprintf("%s probe %s: %s (failure_streak=%d)",
+		event.Type, event.Probe, event.Reason, event.FailureStreak)
+
+	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", n.conf.BotToken)
+	form := url.Values{
+		"chat_id": {n.conf.ChatID},
+		"text":    {text},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("telegram notifier received status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerProber("tls", &tlsProber{})
+}
+
+// defaultTLSMinDays is how many days out a leaf certificate must remain
+// valid when a `tls://` target does not set min_days.
+const defaultTLSMinDays = 14
+
+// tlsProber probes a `tls://host:port?min_days=14` target by completing a
+// TLS handshake and failing if the leaf certificate expires within
+// min_days.
+type tlsProber struct{}
+
+func (p *tlsProber) Check(ctx context.Context, conf *ProbeConfig, target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return err
+	}
+	addr := u.Host
+	if strings.LastIndex(addr, ":") < 0 {
+		addr += ":443"
+	}
+
+	minDays := defaultTLSMinDays
+	if v := u.Query().Get("min_days"); v != "" {
+		minDays, err = strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{}}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return fmt.Errorf("tls probe %q did not produce a TLS connection", target)
+	}
+
+	certs := tlsConn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return fmt.Errorf("tls probe %q presented no certificates", target)
+	}
+
+	expiry := certs[0].NotAfter
+	if time.Until(expiry) < time.Duration(minDays)*24*time.Hour {
+		return fmt.Errorf("tls probe %q certificate expires %s, within %d days", target, expiry, minDays)
+	}
+	return nil
+}
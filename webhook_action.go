@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+func init() {
+	registerAction("webhook", func(conf ResetActionConfig) (Action, error) {
+		return &webhookAction{conf: conf.Webhook}, nil
+	})
+}
+
+// WebhookActionConfig holds the settings for the webhook reset action.
+type WebhookActionConfig struct {
+	URL         string `yaml:"url"`
+	BearerToken string `yaml:"bearer_token"`
+	HMACSecret  string `yaml:"hmac_secret"`
+}
+
+// webhookResetPayload is the JSON body posted to a webhook reset action.
+type webhookResetPayload struct {
+	Probe         string    `json:"probe"`
+	Reason        string    `json:"reason"`
+	FailureStreak int       `json:"failure_streak"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// webhookAction triggers recovery by POSTing to an external URL, for
+// cloud APIs and appliances with no SSH surface.
+type webhookAction struct {
+	conf WebhookActionConfig
+}
+
+func (a *webhookAction) Execute(ctx context.Context, probe *ProbeConfig) (string, error) {
+	payload, err := json.Marshal(webhookResetPayload{
+		Probe:         probe.Name,
+		Reason:        "probe failure streak reached threshold",
+		FailureStreak: FailureStreakFromContext(ctx),
+		Timestamp:     time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.conf.URL, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.conf.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+a.conf.BearerToken)
+	}
+	if a.conf.HMACSecret != "" {
+		mac := hmac.New(sha256.New, []byte(a.conf.HMACSecret))
+		mac.Write(payload)
+		req.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return string(body), fmt.Errorf("webhook reset action returned status %d", resp.StatusCode)
+	}
+	return string(body), nil
+}
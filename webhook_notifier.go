@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func init() {
+	registerNotifier("webhook", func(conf NotifierConfig) (Notifier, error) {
+		return &webhookNotifier{conf: conf.Webhook}, nil
+	})
+}
+
+// WebhookNotifierConfig holds settings for the generic webhook notifier.
+type WebhookNotifierConfig struct {
+	URL        string `yaml:"url"`
+	HMACSecret string `yaml:"hmac_secret"`
+}
+
+// webhookNotifierPayload is the JSON body posted to a webhook notifier.
+type webhookNotifierPayload struct {
+	Probe         string `json:"probe"`
+	Event         string `json:"event"`
+	Reason        string `json:"reason"`
+	FailureStreak int    `json:"failure_streak"`
+	ActionOutput  string `json:"action_output"`
+	DurationMs    int64  `json:"duration_ms"`
+	Timestamp     string `json:"timestamp"`
+}
+
+// webhookNotifier POSTs the event as JSON, optionally HMAC-signed.
+type webhookNotifier struct {
+	conf WebhookNotifierConfig
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, event NotifyEvent) error {
+	payload, err := json.Marshal(webhookNotifierPayload{
+		Probe:         event.Probe,
+		Event:         string(event.Type),
+		Reason:        event.Reason,
+		FailureStreak: event.FailureStreak,
+		ActionOutput:  event.ActionOutput,
+		DurationMs:    event.Duration.Milliseconds(),
+		Timestamp:     event.Timestamp.Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.conf.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.conf.HMACSecret != "" {
+		mac := hmac.New(sha256.New, []byte(n.conf.HMACSecret))
+		mac.Write(payload)
+		req.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook notifier received status %d", resp.StatusCode)
+	}
+	return nil
+}